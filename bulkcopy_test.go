@@ -59,6 +59,10 @@ func TestBulkcopy(t *testing.T) {
 		{"test_bigintn", nil},
 		{"test_geom", geom},
 		{"test_uniqueidentifier", []byte{0x6F, 0x96, 0x19, 0xFF, 0x8B, 0x86, 0xD0, 0x11, 0xB4, 0x2D, 0x00, 0xC0, 0x4F, 0xC9, 0x64, 0xFF}},
+		// test_smallmoney/test_money stay disabled here: encoding them
+		// correctly requires hooking DecimalToMoney/DecimalToSmallMoney
+		// into the bulk row writer's makeParam path, which isn't wired up
+		// yet. See money_test.go for coverage of the codec itself.
 		// {"test_smallmoney", 1234.56},
 		// {"test_money", 1234.56},
 		{"test_decimal_18_0", 1234.0001},
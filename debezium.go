@@ -0,0 +1,82 @@
+package mssql
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// DebeziumBytes returns the unscaled value of d as the minimum-length
+// two's-complement big-endian byte encoding used by Debezium's
+// org.apache.kafka.connect.data.Decimal logical type, alongside its
+// scale. Zero is encoded as a single 0x00 byte.
+func (d Decimal) DebeziumBytes() (unscaled []byte, scale int32) {
+	x := d.BigInt()
+	return bigIntToTwosComplement(&x), int32(d.scale)
+}
+
+// DecimalFromDebezium is the inverse of DebeziumBytes: it decodes a
+// two's-complement big-endian unscaled value and a scale, as produced by
+// Debezium's Decimal logical type, back into a Decimal.
+func DecimalFromDebezium(unscaled []byte, scale int32) (Decimal, error) {
+	if scale < 0 || scale > math.MaxUint8 {
+		return Decimal{}, fmt.Errorf("mssql: debezium scale %d out of range", scale)
+	}
+	return BigIntToDecimalScale(twosComplementToBigInt(unscaled), uint8(scale))
+}
+
+// VariableScaleDecimal mirrors the {scale, value} struct form Debezium
+// uses for io.debezium.data.VariableScaleDecimal, for NUMERIC columns
+// without a fixed declared scale.
+type VariableScaleDecimal struct {
+	Scale int32
+	Value []byte
+}
+
+// NewVariableScaleDecimal builds the Debezium variable-scale wire struct
+// for d.
+func NewVariableScaleDecimal(d Decimal) VariableScaleDecimal {
+	unscaled, scale := d.DebeziumBytes()
+	return VariableScaleDecimal{Scale: scale, Value: unscaled}
+}
+
+// Decimal decodes v back into a Decimal.
+func (v VariableScaleDecimal) Decimal() (Decimal, error) {
+	return DecimalFromDebezium(v.Value, v.Scale)
+}
+
+// bigIntToTwosComplement encodes x as the minimum-length two's-complement
+// big-endian byte representation, matching java.math.BigInteger.toByteArray.
+func bigIntToTwosComplement(x *big.Int) []byte {
+	if x.Sign() == 0 {
+		return []byte{0}
+	}
+	var bitLen int
+	if x.Sign() > 0 {
+		bitLen = x.BitLen()
+	} else {
+		bitLen = new(big.Int).Sub(new(big.Int).Abs(x), big.NewInt(1)).BitLen()
+	}
+	nBytes := bitLen/8 + 1
+	buf := make([]byte, nBytes)
+	if x.Sign() > 0 {
+		x.FillBytes(buf)
+		return buf
+	}
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(nBytes*8))
+	new(big.Int).Add(mod, x).FillBytes(buf)
+	return buf
+}
+
+// twosComplementToBigInt decodes a minimum-length (or longer)
+// two's-complement big-endian byte representation into a big.Int.
+func twosComplementToBigInt(b []byte) *big.Int {
+	if len(b) == 0 {
+		return new(big.Int)
+	}
+	v := new(big.Int).SetBytes(b)
+	if b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return v
+}
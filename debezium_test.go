@@ -0,0 +1,79 @@
+package mssql
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDebeziumBytesZero(t *testing.T) {
+	dec, err := StringToDecimal("0.00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unscaled, scale := dec.DebeziumBytes()
+	if !bytes.Equal(unscaled, []byte{0x00}) {
+		t.Errorf("DebeziumBytes(0.00) unscaled = % X, want [00]", unscaled)
+	}
+	if scale != 2 {
+		t.Errorf("DebeziumBytes(0.00) scale = %d, want 2", scale)
+	}
+}
+
+func TestDebeziumBytesNegativeBoundary(t *testing.T) {
+	// -128 fits in a single two's-complement byte (0x80); -129 does not
+	// and needs two bytes (0xFF 0x7F). This is exactly the kind of
+	// off-by-one a two's-complement encoder gets wrong.
+	d128, err := StringToDecimal("-128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unscaled, _ := d128.DebeziumBytes()
+	if !bytes.Equal(unscaled, []byte{0x80}) {
+		t.Errorf("DebeziumBytes(-128) = % X, want [80]", unscaled)
+	}
+
+	d129, err := StringToDecimal("-129")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unscaled, _ = d129.DebeziumBytes()
+	if !bytes.Equal(unscaled, []byte{0xFF, 0x7F}) {
+		t.Errorf("DebeziumBytes(-129) = % X, want [FF 7F]", unscaled)
+	}
+}
+
+func TestDebeziumRoundTrip(t *testing.T) {
+	cases := []string{"0", "0.00", "-128", "-129", "127", "128", "1234.5678", "-66666666666666666666.6666666666"}
+	for _, c := range cases {
+		dec, err := StringToDecimal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unscaled, scale := dec.DebeziumBytes()
+		got, err := DecimalFromDebezium(unscaled, scale)
+		if err != nil {
+			t.Fatalf("DecimalFromDebezium(%q): %v", c, err)
+		}
+		if !got.Equal(dec) {
+			t.Errorf("round trip %q: got %s, want %s", c, got.String(), dec.String())
+		}
+	}
+}
+
+func TestVariableScaleDecimal(t *testing.T) {
+	dec, err := StringToDecimal("42.125")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vsd := NewVariableScaleDecimal(dec)
+	if vsd.Scale != 3 {
+		t.Errorf("VariableScaleDecimal.Scale = %d, want 3", vsd.Scale)
+	}
+	got, err := vsd.Decimal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(dec) {
+		t.Errorf("VariableScaleDecimal round trip: got %s, want %s", got.String(), dec.String())
+	}
+}
@@ -1,11 +1,13 @@
 package mssql
 
 import (
+	"database/sql/driver"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
+	"strconv"
 	"strings"
 )
 
@@ -36,41 +38,113 @@ func (d Decimal) ToFloat64() float64 {
 
 const autoScale = 100
 
+// maxDecimalPrecision is the largest number of decimal digits that fit in
+// the 128-bit unscaled integer backing a Decimal (matching SQL Server's
+// decimal(38, s) ceiling).
+const maxDecimalPrecision = 38
+
+// tenPowInt[i] holds 10^i as a *big.Int, used throughout the big.Int/
+// big.Rat/big.Float conversion helpers below.
+var tenPowInt [maxDecimalPrecision + 1]*big.Int
+
+// maxDecimalBigInt is 10^38, the largest unscaled absolute value a
+// Decimal can hold.
+var maxDecimalBigInt *big.Int
+
 func Float64ToDecimal(f float64) (Decimal, error) {
 	return Float64ToDecimalScale(f, autoScale)
 }
 
+// Float64ToDecimalScale converts f to a Decimal with the given scale,
+// routing through math/big so that the conversion is exact for every
+// float64 value instead of accumulating binary rounding error. When
+// scale is autoScale, the smallest scale that represents f exactly is
+// chosen; where no scale up to maxDecimalPrecision does (as for 1234.56),
+// the scale is the number of fractional digits in the shortest decimal
+// that converts back to f.
 func Float64ToDecimalScale(f float64, scale uint8) (Decimal, error) {
-	var dec Decimal
 	if math.IsNaN(f) {
-		return dec, errors.New("NaN")
+		return Decimal{}, errors.New("NaN")
 	}
 	if math.IsInf(f, 0) {
-		return dec, errors.New("Infinity can't be converted to decimal")
-	}
-	dec.positive = f >= 0
-	if !dec.positive {
-		f = math.Abs(f)
-	}
-	if f > 3.402823669209385e+38 {
-		return dec, errors.New("Float value is out of range")
-	}
-	dec.prec = 20
-	var integer float64
-	for dec.scale = 0; dec.scale <= scale; dec.scale++ {
-		integer = f * scaletblflt64[dec.scale]
-		_, frac := math.Modf(integer)
-		if frac == 0 && scale == autoScale {
-			break
+		return Decimal{}, errors.New("Infinity can't be converted to decimal")
+	}
+	if math.Abs(f) > 3.402823669209385e+38 {
+		return Decimal{}, errors.New("Float value is out of range")
+	}
+	r, _ := new(big.Float).SetPrec(200).SetFloat64(f).Rat(nil)
+	if scale == autoScale {
+		return bigRatToDecimalAutoScale(r)
+	}
+	return BigRatToDecimalScale(r, scale)
+}
+
+// BigIntToDecimalScale builds a Decimal whose unscaled value is exactly
+// x, at the given scale. It returns an error if x does not fit in the
+// 128-bit unscaled range (|x| >= 10^38).
+func BigIntToDecimalScale(x *big.Int, scale uint8) (Decimal, error) {
+	if new(big.Int).Abs(x).Cmp(maxDecimalBigInt) >= 0 {
+		return Decimal{}, fmt.Errorf("mssql: %s exceeds maximum decimal precision (10^%d)", x.String(), maxDecimalPrecision)
+	}
+	return decimalFromBigInt(x, scale), nil
+}
+
+// BigRatToDecimalScale rounds r to the given scale using round-half-to-
+// even, the same rounding T-SQL uses when assigning into a decimal(p,s)
+// column, and packs the result into a Decimal.
+func BigRatToDecimalScale(r *big.Rat, scale uint8) (Decimal, error) {
+	if scale > maxDecimalPrecision {
+		return Decimal{}, fmt.Errorf("mssql: scale %d exceeds maximum decimal precision (%d)", scale, maxDecimalPrecision)
+	}
+	numAbs := new(big.Int).Mul(new(big.Int).Abs(r.Num()), tenPowInt[scale])
+	denomAbs := r.Denom() // Denom() is always positive
+
+	q, rem := new(big.Int).QuoRem(numAbs, denomAbs, new(big.Int))
+	twiceRem := new(big.Int).Lsh(rem, 1)
+	if cmp := twiceRem.Cmp(denomAbs); cmp > 0 || (cmp == 0 && q.Bit(0) == 1) {
+		q.Add(q, big.NewInt(1))
+	}
+	if q.Cmp(maxDecimalBigInt) >= 0 {
+		return Decimal{}, fmt.Errorf("mssql: %s exceeds maximum decimal precision (10^%d)", r.FloatString(int(scale)), maxDecimalPrecision)
+	}
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	return decimalFromBigInt(q, scale), nil
+}
+
+// BigFloatToDecimalScale rounds f to the given scale and packs the
+// result into a Decimal, using exact big.Rat arithmetic so that the
+// conversion never accumulates binary floating point error.
+func BigFloatToDecimalScale(f *big.Float, scale uint8) (Decimal, error) {
+	r, _ := f.Rat(nil)
+	if r == nil {
+		return Decimal{}, fmt.Errorf("mssql: %s can't be converted to decimal", f.Text('g', -1))
+	}
+	return BigRatToDecimalScale(r, scale)
+}
+
+// bigRatToDecimalAutoScale picks the smallest scale (0..maxDecimalPrecision)
+// at which r is represented exactly. If there is none, it uses the
+// number of fractional digits in the shortest decimal form of r's nearest
+// float64 (capped at maxDecimalPrecision) and rounds r to that scale.
+func bigRatToDecimalAutoScale(r *big.Rat) (Decimal, error) {
+	for scale := uint8(0); scale < maxDecimalPrecision; scale++ {
+		num := new(big.Int).Mul(r.Num(), tenPowInt[scale])
+		if new(big.Int).Mod(num, r.Denom()).Sign() == 0 {
+			return BigRatToDecimalScale(r, scale)
 		}
 	}
-	for i := 0; i < 4; i++ {
-		mod := math.Mod(integer, 0x100000000)
-		integer -= mod
-		integer /= 0x100000000
-		dec.integer[i] = uint32(mod)
+	f, _ := r.Float64()
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	scale := 0
+	if point := strings.IndexByte(s, '.'); point != -1 {
+		scale = len(s) - point - 1
 	}
-	return dec, nil
+	if scale > maxDecimalPrecision {
+		scale = maxDecimalPrecision
+	}
+	return BigRatToDecimalScale(r, uint8(scale))
 }
 
 func Int64ToDecimalScale(v int64, scale uint8) Decimal {
@@ -95,43 +169,70 @@ func Int64ToDecimalScale(v int64, scale uint8) Decimal {
 	}
 }
 
+// StringToDecimal parses v, which may be a plain decimal literal
+// ("-1234.56") or use scientific notation ("6.6666666666E+19", as
+// produced by strconv.FormatFloat(..., 'e', ...), JSON numbers, or
+// T-SQL CAST(... AS varchar) on very small/large numerics).
 func StringToDecimal(v string) (Decimal, error) {
-	var r big.Int
-	var unscaled string
-	var scale int
+	mantissa := v
+	exponent := 0
+	if idx := strings.IndexAny(v, "eE"); idx != -1 {
+		mantissa = v[:idx]
+		e, err := strconv.ParseInt(v[idx+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("Can't parse %q as a decimal number: bad exponent", v)
+		}
+		exponent = int(e)
+	}
 
-	point := strings.LastIndexByte(v, '.')
+	var unscaled string
+	var fracDigits int
+	point := strings.LastIndexByte(mantissa, '.')
 	if point == -1 {
-		scale = 0
-		unscaled = v
+		unscaled = mantissa
+		fracDigits = 0
 	} else {
-		scale = len(v) - point - 1
-		unscaled = v[:point] + v[point+1:]
-	}
-	if scale > math.MaxUint8 {
-		return Decimal{}, fmt.Errorf("Can't parse %q as a decimal number: scale too large", v)
+		fracDigits = len(mantissa) - point - 1
+		unscaled = mantissa[:point] + mantissa[point+1:]
 	}
 
-	_, ok := r.SetString(unscaled, 10)
-	if !ok {
+	var r big.Int
+	if _, ok := r.SetString(unscaled, 10); !ok {
 		return Decimal{}, fmt.Errorf("Can't parse %q as a decimal number", v)
 	}
 
-	bytes := r.Bytes()
-	if len(bytes) > 16 {
+	// scale is fractional_digits - exponent: a positive exponent eats
+	// into the fractional digits already present, and can push the
+	// effective scale below zero, in which case the unscaled integer
+	// needs to be shifted left to absorb the difference.
+	scale := fracDigits - exponent
+	if scale < 0 {
+		if scale < -2*maxDecimalPrecision {
+			return Decimal{}, fmt.Errorf("Can't parse %q as a decimal number: exponent out of range", v)
+		}
+		r.Mul(&r, pow10(uint8(-scale)))
+		scale = 0
+	}
+	if scale > maxDecimalPrecision {
+		return Decimal{}, fmt.Errorf("Can't parse %q as a decimal number: scale too large", v)
+	}
+
+	if new(big.Int).Abs(&r).Cmp(maxDecimalBigInt) >= 0 {
 		return Decimal{}, fmt.Errorf("Can't parse %q as a decimal number: precision too large", v)
 	}
-	var out [4]uint32
-	for i, b := range bytes {
-		pos := len(bytes) - i - 1
-		out[pos/4] += uint32(b) << uint(pos%4*8)
+
+	return decimalFromBigInt(&r, uint8(scale)), nil
+}
+
+// MustStringToDecimal is like StringToDecimal but panics if v cannot be
+// parsed, matching the ergonomics of big.Int.SetString's callers that
+// know their input is well-formed.
+func MustStringToDecimal(v string) Decimal {
+	d, err := StringToDecimal(v)
+	if err != nil {
+		panic(err)
 	}
-	return Decimal{
-		integer:  out,
-		positive: r.Sign() >= 0,
-		prec:     20,
-		scale:    uint8(scale),
-	}, nil
+	return d
 }
 
 func init() {
@@ -140,6 +241,14 @@ func init() {
 		scaletblflt64[i] = acc
 		acc *= 10
 	}
+
+	accInt := big.NewInt(1)
+	ten := big.NewInt(10)
+	for i := 0; i <= maxDecimalPrecision; i++ {
+		tenPowInt[i] = new(big.Int).Set(accInt)
+		accInt.Mul(accInt, ten)
+	}
+	maxDecimalBigInt = new(big.Int).Set(tenPowInt[maxDecimalPrecision])
 }
 
 func (d Decimal) BigInt() big.Int {
@@ -192,3 +301,214 @@ func scaleBytes(s string, scale uint8) []byte {
 func (d Decimal) String() string {
 	return string(d.Bytes())
 }
+
+// Scan implements the sql.Scanner interface so a Decimal can be used
+// directly as a destination in rows.Scan, e.g. for decimal/numeric
+// columns whose precision would otherwise be lost going through float64.
+//
+// Scan itself never touches float64 for its []byte/string/Decimal cases,
+// but whether a decimal/numeric column actually reaches it that way still
+// depends on what the column-type/value-conversion dispatch in the read
+// path (outside this file) hands to Scan in the first place; that dispatch
+// is not changed here.
+func (d *Decimal) Scan(v interface{}) error {
+	switch src := v.(type) {
+	case nil:
+		return errors.New("mssql: cannot scan NULL into *Decimal, use *NullDecimal instead")
+	case Decimal:
+		*d = src
+		return nil
+	case []byte:
+		dec, err := StringToDecimal(string(src))
+		if err != nil {
+			return err
+		}
+		*d = dec
+		return nil
+	case string:
+		dec, err := StringToDecimal(src)
+		if err != nil {
+			return err
+		}
+		*d = dec
+		return nil
+	case int64:
+		*d = Int64ToDecimalScale(src, 0)
+		return nil
+	case float64:
+		dec, err := Float64ToDecimal(src)
+		if err != nil {
+			return err
+		}
+		*d = dec
+		return nil
+	case *big.Int:
+		dec, err := BigIntToDecimalScale(src, 0)
+		if err != nil {
+			return err
+		}
+		*d = dec
+		return nil
+	default:
+		return fmt.Errorf("mssql: cannot scan %T into Decimal", v)
+	}
+}
+
+// Value implements the driver.Valuer interface, returning the canonical
+// decimal string so that existing parameter binding round-trips without
+// loss of precision.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// NullDecimal represents a Decimal that may be NULL. It mirrors
+// sql.NullString and implements the sql.Scanner and driver.Valuer
+// interfaces so it can be used as a destination or parameter for
+// nullable decimal/numeric columns.
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool
+}
+
+func (n *NullDecimal) Scan(v interface{}) error {
+	if v == nil {
+		n.Decimal, n.Valid = Decimal{}, false
+		return nil
+	}
+	n.Valid = true
+	return n.Decimal.Scan(v)
+}
+
+func (n NullDecimal) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Decimal.Value()
+}
+
+// pow10 returns 10^n as a *big.Int, using the precomputed tenPowInt table
+// when n is in range and falling back to big.Int.Exp otherwise.
+func pow10(n uint8) *big.Int {
+	if int(n) < len(tenPowInt) {
+		return tenPowInt[n]
+	}
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// bigRat returns the exact value of d as unscaled / 10^scale.
+func (d Decimal) bigRat() *big.Rat {
+	x := d.BigInt()
+	return new(big.Rat).SetFrac(&x, pow10(d.scale))
+}
+
+// Add returns d + other, rounded half-to-even to the larger of the two
+// operands' scales.
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	scale := d.scale
+	if other.scale > scale {
+		scale = other.scale
+	}
+	return BigRatToDecimalScale(new(big.Rat).Add(d.bigRat(), other.bigRat()), scale)
+}
+
+// Sub returns d - other, rounded half-to-even to the larger of the two
+// operands' scales.
+func (d Decimal) Sub(other Decimal) (Decimal, error) {
+	scale := d.scale
+	if other.scale > scale {
+		scale = other.scale
+	}
+	return BigRatToDecimalScale(new(big.Rat).Sub(d.bigRat(), other.bigRat()), scale)
+}
+
+// Mul returns d * other at scale d.scale+other.scale, matching T-SQL's
+// decimal multiplication scale. It errors if that combined scale would
+// exceed the maximum decimal precision.
+func (d Decimal) Mul(other Decimal) (Decimal, error) {
+	scale := int(d.scale) + int(other.scale)
+	if scale > maxDecimalPrecision {
+		return Decimal{}, fmt.Errorf("mssql: result scale %d exceeds maximum decimal precision (%d)", scale, maxDecimalPrecision)
+	}
+	return BigRatToDecimalScale(new(big.Rat).Mul(d.bigRat(), other.bigRat()), uint8(scale))
+}
+
+// Div returns d / other rounded half-to-even to resultScale.
+func (d Decimal) Div(other Decimal, resultScale uint8) (Decimal, error) {
+	divisor := other.bigRat()
+	if divisor.Sign() == 0 {
+		return Decimal{}, errors.New("mssql: division by zero")
+	}
+	return BigRatToDecimalScale(new(big.Rat).Quo(d.bigRat(), divisor), resultScale)
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	n := d
+	bi := n.BigInt()
+	if bi.Sign() != 0 {
+		n.positive = !n.positive
+	}
+	return n
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	n := d
+	n.positive = true
+	return n
+}
+
+// Cmp compares d and other as exact values, independent of scale, and
+// returns -1, 0, or +1 as d is less than, equal to, or greater than
+// other.
+func (d Decimal) Cmp(other Decimal) int {
+	return d.bigRat().Cmp(other.bigRat())
+}
+
+// Equal reports whether d and other represent the same value, even if
+// their scales differ (e.g. 1.50 and 1.5).
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Rescale rounds d to newScale using round-half-to-even, erroring if the
+// result would overflow the 128-bit unscaled range.
+func (d Decimal) Rescale(newScale uint8) (Decimal, error) {
+	return BigRatToDecimalScale(d.bigRat(), newScale)
+}
+
+// Truncate returns d with its fractional digits beyond scale discarded
+// towards zero, without rounding. Since it only ever discards digits,
+// scale must be <= d.scale; if it isn't, there is nothing to truncate
+// and d is returned unchanged rather than growing the unscaled value
+// (which is Rescale's job, and can overflow).
+func (d Decimal) Truncate(scale uint8) Decimal {
+	if scale >= d.scale {
+		return d
+	}
+	r := d.bigRat()
+	numAbs := new(big.Int).Mul(new(big.Int).Abs(r.Num()), pow10(scale))
+	q := new(big.Int).Quo(numAbs, r.Denom())
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	return decimalFromBigInt(q, scale)
+}
+
+// decimalFromBigInt packs the absolute value of x into the little-endian
+// [4]uint32 layout used by Decimal, recording its sign separately.
+func decimalFromBigInt(x *big.Int, scale uint8) Decimal {
+	abs := new(big.Int).Abs(x)
+	bytes := abs.Bytes()
+	var out [4]uint32
+	for i, b := range bytes {
+		pos := len(bytes) - i - 1
+		out[pos/4] += uint32(b) << uint(pos%4*8)
+	}
+	return Decimal{
+		integer:  out,
+		positive: x.Sign() >= 0,
+		prec:     20,
+		scale:    scale,
+	}
+}
@@ -0,0 +1,323 @@
+package mssql
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestDecimalScanPreservesPrecision(t *testing.T) {
+	const want = "66666666666666666666.6666666666"
+	var dec Decimal
+	if err := dec.Scan(want); err != nil {
+		t.Fatal(err)
+	}
+	if got := dec.String(); got != want {
+		t.Errorf("Scan(%q).String() = %q, want %q (precision lost, likely via float64)", want, got, want)
+	}
+
+	var dec2 Decimal
+	if err := dec2.Scan([]byte(want)); err != nil {
+		t.Fatal(err)
+	}
+	if got := dec2.String(); got != want {
+		t.Errorf("Scan([]byte(%q)).String() = %q, want %q", want, got, want)
+	}
+}
+
+func TestDecimalScanDecimal(t *testing.T) {
+	src, err := StringToDecimal("1234.5600")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Decimal
+	if err := dec.Scan(src); err != nil {
+		t.Fatal(err)
+	}
+	if !dec.Equal(src) {
+		t.Errorf("Scan(Decimal) = %s, want %s", dec.String(), src.String())
+	}
+}
+
+func TestDecimalValue(t *testing.T) {
+	dec, err := StringToDecimal("-1234.5600")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := dec.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != dec.String() {
+		t.Errorf("Value() = %v, want %s", v, dec.String())
+	}
+}
+
+func TestNullDecimalScan(t *testing.T) {
+	var n NullDecimal
+	if err := n.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if n.Valid {
+		t.Error("Scan(nil) should leave Valid false")
+	}
+	if v, err := n.Value(); err != nil || v != nil {
+		t.Errorf("Value() for invalid NullDecimal = (%v, %v), want (nil, nil)", v, err)
+	}
+
+	if err := n.Scan("42.5"); err != nil {
+		t.Fatal(err)
+	}
+	if !n.Valid {
+		t.Error("Scan(\"42.5\") should set Valid true")
+	}
+	if n.Decimal.String() != "42.5" {
+		t.Errorf("Decimal = %s, want 42.5", n.Decimal.String())
+	}
+}
+
+func TestDecimalAddSub(t *testing.T) {
+	a, _ := StringToDecimal("1.25")
+	b, _ := StringToDecimal("0.125")
+
+	sum, err := a.Add(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1.375"; sum.String() != want {
+		t.Errorf("Add: got %s, want %s", sum.String(), want)
+	}
+
+	diff, err := a.Sub(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1.125"; diff.String() != want {
+		t.Errorf("Sub: got %s, want %s", diff.String(), want)
+	}
+}
+
+func TestDecimalMul(t *testing.T) {
+	a, _ := StringToDecimal("1.5")
+	b, _ := StringToDecimal("0.2")
+	product, err := a.Mul(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0.30"; product.String() != want {
+		t.Errorf("Mul: got %s, want %s", product.String(), want)
+	}
+}
+
+func TestDecimalDiv(t *testing.T) {
+	a, _ := StringToDecimal("1")
+	b, _ := StringToDecimal("3")
+	q, err := a.Div(b, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0.3333"; q.String() != want {
+		t.Errorf("Div: got %s, want %s", q.String(), want)
+	}
+
+	zero, _ := StringToDecimal("0")
+	if _, err := a.Div(zero, 4); err == nil {
+		t.Error("Div by zero should return an error")
+	}
+}
+
+func TestDecimalRescaleHalfToEven(t *testing.T) {
+	cases := []struct {
+		in, want string
+		scale    uint8
+	}{
+		{"0.125", "0.12", 2}, // tie rounds to even (2)
+		{"0.135", "0.14", 2}, // tie rounds to even (4)
+		{"0.15", "0.2", 1},
+	}
+	for _, c := range cases {
+		d, err := StringToDecimal(c.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := d.Rescale(c.scale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != c.want {
+			t.Errorf("Rescale(%s, %d) = %s, want %s", c.in, c.scale, got.String(), c.want)
+		}
+	}
+}
+
+func TestDecimalTruncateVsRescale(t *testing.T) {
+	d, _ := StringToDecimal("1.999")
+
+	truncated := d.Truncate(1)
+	if want := "1.9"; truncated.String() != want {
+		t.Errorf("Truncate(1) = %s, want %s", truncated.String(), want)
+	}
+
+	// Truncate only ever discards digits; asking for a larger scale than
+	// the value already has must be a no-op rather than growing the
+	// unscaled value (which is Rescale's job, and can overflow).
+	if noop := d.Truncate(10); noop.String() != d.String() {
+		t.Errorf("Truncate(10) on a scale-3 value = %s, want %s (no-op)", noop.String(), d.String())
+	}
+
+	rescaled, err := d.Rescale(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2.0"; rescaled.String() != want {
+		t.Errorf("Rescale(1) = %s, want %s (rounds, unlike Truncate)", rescaled.String(), want)
+	}
+}
+
+func TestDecimalCmpEqualAcrossScales(t *testing.T) {
+	a, _ := StringToDecimal("1.50")
+	b, _ := StringToDecimal("1.5")
+	if !a.Equal(b) {
+		t.Errorf("%s should equal %s despite differing scales", a.String(), b.String())
+	}
+	if a.Cmp(b) != 0 {
+		t.Errorf("Cmp(%s, %s) = %d, want 0", a.String(), b.String(), a.Cmp(b))
+	}
+
+	c, _ := StringToDecimal("1.51")
+	if a.Cmp(c) >= 0 {
+		t.Errorf("Cmp(%s, %s) should be negative", a.String(), c.String())
+	}
+}
+
+func TestDecimalNegAbs(t *testing.T) {
+	d, _ := StringToDecimal("1.5")
+	if want := "-1.5"; d.Neg().String() != want {
+		t.Errorf("Neg() = %s, want %s", d.Neg().String(), want)
+	}
+	neg, _ := StringToDecimal("-1.5")
+	if want := "1.5"; neg.Abs().String() != want {
+		t.Errorf("Abs() = %s, want %s", neg.Abs().String(), want)
+	}
+	zero, _ := StringToDecimal("0")
+	if want := "0"; zero.Neg().String() != want {
+		t.Errorf("Neg() of zero = %s, want %s", zero.Neg().String(), want)
+	}
+}
+
+func TestFloat64ToDecimalScaleExact(t *testing.T) {
+	dec, err := Float64ToDecimalScale(1234.0001, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1234.0001"; dec.String() != want {
+		t.Errorf("Float64ToDecimalScale(1234.0001, 4) = %s, want %s", dec.String(), want)
+	}
+}
+
+func TestFloat64ToDecimalAutoScale(t *testing.T) {
+	dec, err := Float64ToDecimal(1234.56)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1234.56"; dec.String() != want {
+		t.Errorf("Float64ToDecimal(1234.56) = %s, want %s", dec.String(), want)
+	}
+}
+
+func TestBigIntToDecimalScaleOutOfRange(t *testing.T) {
+	huge, ok := new(big.Int).SetString("100000000000000000000000000000000000000", 10) // 10^38
+	if !ok {
+		t.Fatal("bad test input")
+	}
+	if _, err := BigIntToDecimalScale(huge, 0); err == nil {
+		t.Error("expected error for unscaled value >= 10^38, got nil")
+	}
+
+	ok38nines, good := new(big.Int).SetString("99999999999999999999999999999999999999", 10) // 38 nines
+	if !good {
+		t.Fatal("bad test input")
+	}
+	if _, err := BigIntToDecimalScale(ok38nines, 0); err != nil {
+		t.Errorf("BigIntToDecimalScale(38 nines): unexpected error: %v", err)
+	}
+}
+
+func TestBigRatToDecimalScale(t *testing.T) {
+	r := big.NewRat(1, 3)
+	dec, err := BigRatToDecimalScale(r, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0.33333"; dec.String() != want {
+		t.Errorf("BigRatToDecimalScale(1/3, 5) = %s, want %s", dec.String(), want)
+	}
+}
+
+func TestBigFloatToDecimalScale(t *testing.T) {
+	f := new(big.Float).SetPrec(200).SetFloat64(0.1)
+	dec, err := BigFloatToDecimalScale(f, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0.1"; dec.String() != want {
+		t.Errorf("BigFloatToDecimalScale(0.1, 1) = %s, want %s", dec.String(), want)
+	}
+}
+
+func TestStringToDecimalScientificNotation(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"1.23e-5", "0.0000123"},
+		{"6.6666666666E+19", "66666666666000000000"},
+		{"+1.5e2", "150"},
+		{"-1.5e2", "-150"},
+		{"5e3", "5000"},
+		{"5E-3", "0.005"},
+	}
+	for _, c := range cases {
+		dec, err := StringToDecimal(c.in)
+		if err != nil {
+			t.Fatalf("StringToDecimal(%q): %v", c.in, err)
+		}
+		if got := dec.String(); got != c.want {
+			t.Errorf("StringToDecimal(%q) = %s, want %s", c.in, got, c.want)
+		}
+	}
+}
+
+func TestStringToDecimalExponentOutOfRange(t *testing.T) {
+	if _, err := StringToDecimal("1e400"); err == nil {
+		t.Error("expected error for an absurdly large exponent, got nil")
+	}
+	if _, err := StringToDecimal("1e-400"); err == nil {
+		t.Error("expected error for an absurdly small exponent, got nil")
+	}
+	if _, err := StringToDecimal("1ebad"); err == nil {
+		t.Error("expected error for a malformed exponent, got nil")
+	}
+}
+
+func TestStringToDecimalPrecisionBoundary(t *testing.T) {
+	if _, err := StringToDecimal("100000000000000000000000000000000000000"); err == nil { // 10^38
+		t.Error("expected error for a 39-digit unscaled value, got nil")
+	}
+	if _, err := StringToDecimal("99999999999999999999999999999999999999"); err != nil { // 38 nines
+		t.Errorf("38-digit unscaled value should be accepted: %v", err)
+	}
+}
+
+func TestMustStringToDecimalPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustStringToDecimal should panic on invalid input")
+		}
+	}()
+	MustStringToDecimal("not a decimal")
+}
+
+func TestMustStringToDecimal(t *testing.T) {
+	if got, want := MustStringToDecimal("12.5").String(), "12.5"; got != want {
+		t.Errorf("MustStringToDecimal(\"12.5\") = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package mssql
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// NOTE: this file implements the money/smallmoney <-> Decimal codec only.
+// It is not yet called from the bulk row writer's makeDecl/makeParam path
+// (that dispatch lives in files this change doesn't touch), so bulk copy
+// of money/smallmoney columns is still unsupported end-to-end; see the
+// still-disabled test_smallmoney/test_money rows in bulkcopy_test.go.
+// These functions are exercised directly by money_test.go in the
+// meantime.
+
+// moneyScale is the fixed scale (four decimal digits) SQL Server uses for
+// its money and smallmoney fixed-point TDS wire types.
+const moneyScale = 4
+
+// DecimalToMoney packs d into the 8-byte little-endian MONEYNTYPE wire
+// representation (two 32-bit halves, units of 1/10000), rescaling to
+// moneyScale first.
+func DecimalToMoney(d Decimal) ([]byte, error) {
+	scaled, err := d.Rescale(moneyScale)
+	if err != nil {
+		return nil, err
+	}
+	v := scaled.BigInt()
+	if !v.IsInt64() {
+		return nil, fmt.Errorf("mssql: value %s out of range for money", d.String())
+	}
+	i := v.Int64()
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(i>>32))
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(i))
+	return buf, nil
+}
+
+// DecimalToSmallMoney packs d into the 4-byte little-endian smallmoney
+// wire representation (units of 1/10000), rescaling to moneyScale first.
+func DecimalToSmallMoney(d Decimal) ([]byte, error) {
+	scaled, err := d.Rescale(moneyScale)
+	if err != nil {
+		return nil, err
+	}
+	v := scaled.BigInt()
+	if !v.IsInt64() {
+		return nil, fmt.Errorf("mssql: value %s out of range for smallmoney", d.String())
+	}
+	i := v.Int64()
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return nil, fmt.Errorf("mssql: value %s out of range for smallmoney", d.String())
+	}
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, uint32(int32(i)))
+	return buf, nil
+}
+
+// MoneyFromBytes decodes the 8-byte money wire representation (two
+// little-endian 32-bit halves) into a Decimal at scale 4.
+func MoneyFromBytes(b []byte) (Decimal, error) {
+	if len(b) != 8 {
+		return Decimal{}, fmt.Errorf("mssql: money value must be 8 bytes, got %d", len(b))
+	}
+	hi := int64(int32(binary.LittleEndian.Uint32(b[0:4])))
+	lo := int64(binary.LittleEndian.Uint32(b[4:8]))
+	return decimalFromBigInt(big.NewInt(hi<<32|lo), moneyScale), nil
+}
+
+// SmallMoneyFromBytes decodes the 4-byte smallmoney wire representation
+// into a Decimal at scale 4.
+func SmallMoneyFromBytes(b []byte) (Decimal, error) {
+	if len(b) != 4 {
+		return Decimal{}, fmt.Errorf("mssql: smallmoney value must be 4 bytes, got %d", len(b))
+	}
+	v := int32(binary.LittleEndian.Uint32(b))
+	return Int64ToDecimalScale(int64(v), moneyScale), nil
+}
+
+// ValueToDecimalMoney converts the source value types accepted for
+// money/smallmoney bulk copy parameters (Decimal, int64, float64, string,
+// *big.Rat) into a Decimal at moneyScale, so that precision is preserved
+// regardless of the Go source type before it reaches DecimalToMoney or
+// DecimalToSmallMoney.
+func ValueToDecimalMoney(v interface{}) (Decimal, error) {
+	switch src := v.(type) {
+	case Decimal:
+		return src.Rescale(moneyScale)
+	case int64:
+		return Int64ToDecimalScale(src, 0).Rescale(moneyScale)
+	case float64:
+		return Float64ToDecimalScale(src, moneyScale)
+	case string:
+		dec, err := StringToDecimal(src)
+		if err != nil {
+			return Decimal{}, err
+		}
+		return dec.Rescale(moneyScale)
+	case *big.Rat:
+		return BigRatToDecimalScale(src, moneyScale)
+	default:
+		return Decimal{}, fmt.Errorf("mssql: cannot convert %T to money", v)
+	}
+}
@@ -0,0 +1,62 @@
+package mssql
+
+import "testing"
+
+func TestMoneyRoundTrip(t *testing.T) {
+	dec, err := StringToDecimal("1234.5600")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := DecimalToMoney(dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := MoneyFromBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(dec) {
+		t.Errorf("money round trip: got %s, want %s", got.String(), dec.String())
+	}
+}
+
+func TestSmallMoneyRoundTrip(t *testing.T) {
+	dec, err := StringToDecimal("-1234.5600")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := DecimalToSmallMoney(dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := SmallMoneyFromBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(dec) {
+		t.Errorf("smallmoney round trip: got %s, want %s", got.String(), dec.String())
+	}
+}
+
+func TestSmallMoneyOutOfRange(t *testing.T) {
+	dec, err := StringToDecimal("300000.0000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecimalToSmallMoney(dec); err == nil {
+		t.Error("expected out-of-range error for smallmoney, got nil")
+	}
+}
+
+func TestValueToDecimalMoney(t *testing.T) {
+	cases := []interface{}{
+		int64(1234),
+		1234.56,
+		"1234.5600",
+	}
+	for _, v := range cases {
+		if _, err := ValueToDecimalMoney(v); err != nil {
+			t.Errorf("ValueToDecimalMoney(%#v): %v", v, err)
+		}
+	}
+}